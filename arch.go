@@ -0,0 +1,164 @@
+package srcinfo
+
+import "fmt"
+
+// Source describes a single download source for an architecture, with all
+// of its checksums aligned by position so callers no longer have to pair a
+// source_<arch> entry with its matching sums_<arch> entry by index.
+type Source struct {
+	URL    string
+	MD5    string
+	SHA1   string
+	SHA224 string
+	SHA256 string
+	SHA384 string
+	SHA512 string
+}
+
+// ResolvedPackage is a Package collapsed down to a single architecture:
+// every ArchString field has been reduced to the plain values that apply
+// to that architecture (either arch-independent or matching arch), and
+// Source/sums have been merged into Sources by position.
+type ResolvedPackage struct {
+	Pkgdesc      string
+	URL          string
+	License      []string
+	Groups       []string
+	Depends      []string
+	OptDepends   []string
+	Provides     []string
+	Conflicts    []string
+	Replaces     []string
+	Backup       []string
+	Options      []string
+	Install      string
+	Changelog    string
+	MakeDepends  []string
+	CheckDepends []string
+	Sources      []Source
+}
+
+// SupportsArch reports whether the package declares support for arch,
+// either directly or via the "any" architecture.
+func (pkg *Package) SupportsArch(arch string) bool {
+	for _, a := range pkg.Arch {
+		if a == arch || a == "any" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SupportsArch reports whether si's base package declares support for
+// arch, either directly or via the "any" architecture.
+func (si *Srcinfo) SupportsArch(arch string) bool {
+	return si.Package.SupportsArch(arch)
+}
+
+// ForArch collapses si for the given pkgname and architecture, keeping
+// only fields that are arch-independent or that match arch, and pairing
+// up source and checksum entries by position into Sources. It fails if
+// pkgname does not belong to si or if arch is unsupported.
+func (si *Srcinfo) ForArch(pkgname, arch string) (*ResolvedPackage, error) {
+	pkg, err := si.SplitPackage(pkgname)
+	if err != nil {
+		return nil, err
+	}
+
+	if !pkg.SupportsArch(arch) {
+		return nil, fmt.Errorf("package \"%s\" does not support architecture \"%s\"", pkgname, arch)
+	}
+
+	return &ResolvedPackage{
+		Pkgdesc:      pkg.Pkgdesc,
+		URL:          pkg.URL,
+		License:      pkg.License,
+		Groups:       pkg.Groups,
+		Depends:      FilterArch(pkg.Depends, arch),
+		OptDepends:   FilterArch(pkg.OptDepends, arch),
+		Provides:     FilterArch(pkg.Provides, arch),
+		Conflicts:    FilterArch(pkg.Conflicts, arch),
+		Replaces:     FilterArch(pkg.Replaces, arch),
+		Backup:       pkg.Backup,
+		Options:      pkg.Options,
+		Install:      pkg.Install,
+		Changelog:    pkg.Changelog,
+		MakeDepends:  FilterArch(si.MakeDepends, arch),
+		CheckDepends: FilterArch(si.CheckDepends, arch),
+		Sources:      si.sourcesForArch(arch),
+	}, nil
+}
+
+// FilterArch reduces values to the plain strings whose Arch is either
+// empty (arch-independent) or equal to arch, in declaration order. It is
+// exported so other packages building arch-scoped views on top of a
+// Srcinfo -- repodb in particular -- don't have to reimplement it.
+func FilterArch(values []ArchString, arch string) []string {
+	filtered := make([]string, 0, len(values))
+
+	for _, value := range values {
+		if value.Arch == "" || value.Arch == arch {
+			filtered = append(filtered, value.Value)
+		}
+	}
+
+	return filtered
+}
+
+// sourcesForArch aligns source_<arch> (and arch-independent source)
+// entries with their matching sums entries by position, in the order
+// they were declared.
+func (si *Srcinfo) sourcesForArch(arch string) []Source {
+	sources := make([]Source, 0, len(si.Source))
+
+	md5 := FilterArch(si.MD5Sums, arch)
+	sha1 := FilterArch(si.SHA1Sums, arch)
+	sha224 := FilterArch(si.SHA224Sums, arch)
+	sha256 := FilterArch(si.SHA256Sums, arch)
+	sha384 := FilterArch(si.SHA384Sums, arch)
+	sha512 := FilterArch(si.SHA512Sums, arch)
+
+	// i counts only the sources that match arch, so it lines up with the
+	// equally-filtered md5/sha1/.../sha512 slices above -- not with the
+	// unfiltered si.Source index, which would desync as soon as another
+	// architecture's source appears first.
+	i := 0
+
+	for _, value := range si.Source {
+		if value.Arch != "" && value.Arch != arch {
+			continue
+		}
+
+		src := Source{URL: value.Value}
+
+		if i < len(md5) {
+			src.MD5 = md5[i]
+		}
+
+		if i < len(sha1) {
+			src.SHA1 = sha1[i]
+		}
+
+		if i < len(sha224) {
+			src.SHA224 = sha224[i]
+		}
+
+		if i < len(sha256) {
+			src.SHA256 = sha256[i]
+		}
+
+		if i < len(sha384) {
+			src.SHA384 = sha384[i]
+		}
+
+		if i < len(sha512) {
+			src.SHA512 = sha512[i]
+		}
+
+		sources = append(sources, src)
+		i++
+	}
+
+	return sources
+}
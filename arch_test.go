@@ -0,0 +1,47 @@
+package srcinfo
+
+import "testing"
+
+// TestForArchAlignsSumsAcrossArches is a regression test for a bug where a
+// source for one architecture appearing before a source for another caused
+// sourcesForArch to pair checksums with the wrong source, silently leaving
+// them empty instead of erroring.
+func TestForArchAlignsSumsAcrossArches(t *testing.T) {
+	si := &Srcinfo{
+		PackageBase: PackageBase{
+			Pkgbase: "foo",
+			Pkgver:  "1.0",
+			Pkgrel:  "1",
+			Source: []ArchString{
+				{Arch: "i686", Value: "foo-i686.tar.gz"},
+				{Arch: "x86_64", Value: "foo-x86_64.tar.gz"},
+			},
+			SHA256Sums: []ArchString{
+				{Arch: "i686", Value: "i686sum"},
+				{Arch: "x86_64", Value: "x86_64sum"},
+			},
+		},
+		Package: Package{
+			Arch: []string{"i686", "x86_64"},
+		},
+	}
+	si.Pkgnames = []string{"foo"}
+	si.Packages = []Package{{}}
+
+	pkg, err := si.ForArch("foo", "x86_64")
+	if err != nil {
+		t.Fatalf("ForArch: unexpected error: %v", err)
+	}
+
+	if len(pkg.Sources) != 1 {
+		t.Fatalf("len(Sources) = %d, want 1", len(pkg.Sources))
+	}
+
+	if pkg.Sources[0].URL != "foo-x86_64.tar.gz" {
+		t.Fatalf("Sources[0].URL = %q, want %q", pkg.Sources[0].URL, "foo-x86_64.tar.gz")
+	}
+
+	if pkg.Sources[0].SHA256 != "x86_64sum" {
+		t.Errorf("Sources[0].SHA256 = %q, want %q", pkg.Sources[0].SHA256, "x86_64sum")
+	}
+}
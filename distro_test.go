@@ -0,0 +1,128 @@
+package srcinfo
+
+import (
+	"strings"
+	"testing"
+)
+
+const distroSrcinfo = `
+pkgbase = foo
+	pkgdesc = base desc
+	pkgdesc__fedora = fedora desc
+	pkgver = 1.0
+	pkgrel = 1
+	makedepends = gcc
+	makedepends__fedora = gcc-fedora
+	source = foo.tar.gz
+	source_x86_64 = foo-x86_64.tar.gz
+	source_x86_64__fedora = foo-x86_64-fedora.tar.gz
+
+pkgname = foo
+	depends = glibc
+	depends_x86_64__fedora = glibc-fedora
+`
+
+func TestParserDistroKeyComposition(t *testing.T) {
+	si, err := ParseSrcinfoData(distroSrcinfo)
+	if err != nil {
+		t.Fatalf("ParseSrcinfoData: unexpected error: %v", err)
+	}
+
+	override, ok := si.PackageBase.DistroOverrides["fedora"]
+	if !ok {
+		t.Fatal("PackageBase.DistroOverrides[\"fedora\"] missing")
+	}
+
+	if len(override.MakeDepends) != 1 || override.MakeDepends[0] != (ArchString{Value: "gcc-fedora"}) {
+		t.Errorf("MakeDepends override = %+v, want a single arch-independent \"gcc-fedora\"", override.MakeDepends)
+	}
+
+	if len(override.Source) != 1 || override.Source[0] != (ArchString{Arch: "x86_64", Value: "foo-x86_64-fedora.tar.gz"}) {
+		t.Errorf("Source override = %+v, want a single x86_64 entry, i.e. __distro and _arch composed correctly", override.Source)
+	}
+
+	baseOverride, ok := si.Package.DistroOverrides["fedora"]
+	if !ok {
+		t.Fatal("Package.DistroOverrides[\"fedora\"] missing")
+	}
+
+	if baseOverride.Pkgdesc != "fedora desc" {
+		t.Errorf("Pkgdesc override = %q, want %q", baseOverride.Pkgdesc, "fedora desc")
+	}
+
+	pkgOverride, ok := si.Packages[0].DistroOverrides["fedora"]
+	if !ok {
+		t.Fatal("Packages[0].DistroOverrides[\"fedora\"] missing")
+	}
+
+	if len(pkgOverride.Depends) != 1 || pkgOverride.Depends[0] != (ArchString{Arch: "x86_64", Value: "glibc-fedora"}) {
+		t.Errorf("Depends override = %+v, want a single x86_64 entry", pkgOverride.Depends)
+	}
+}
+
+func TestBaseForDistroFallback(t *testing.T) {
+	si, err := ParseSrcinfoData(distroSrcinfo)
+	if err != nil {
+		t.Fatalf("ParseSrcinfoData: unexpected error: %v", err)
+	}
+
+	base := si.BaseForDistro("fedora")
+	if len(base.MakeDepends) != 1 || base.MakeDepends[0].Value != "gcc-fedora" {
+		t.Errorf("BaseForDistro(\"fedora\").MakeDepends = %+v, want the fedora override", base.MakeDepends)
+	}
+
+	if base.Pkgver != "1.0" {
+		t.Errorf("BaseForDistro(\"fedora\").Pkgver = %q, want the unoverridden %q to fall through", base.Pkgver, "1.0")
+	}
+
+	unknown := si.BaseForDistro("arch-linux")
+	if len(unknown.MakeDepends) != 1 || unknown.MakeDepends[0].Value != "gcc" {
+		t.Errorf("BaseForDistro of an unknown distro = %+v, want the plain fields unchanged", unknown.MakeDepends)
+	}
+}
+
+func TestForDistroFallback(t *testing.T) {
+	si, err := ParseSrcinfoData(distroSrcinfo)
+	if err != nil {
+		t.Fatalf("ParseSrcinfoData: unexpected error: %v", err)
+	}
+
+	pkg := si.ForDistro("fedora")
+	if pkg.Pkgdesc != "fedora desc" {
+		t.Errorf("ForDistro(\"fedora\").Pkgdesc = %q, want %q", pkg.Pkgdesc, "fedora desc")
+	}
+
+	unknown := si.ForDistro("arch-linux")
+	if unknown.Pkgdesc != "base desc" {
+		t.Errorf("ForDistro of an unknown distro = %q, want the plain pkgdesc unchanged", unknown.Pkgdesc)
+	}
+}
+
+func TestEncodeDistroRoundTrip(t *testing.T) {
+	si, err := ParseSrcinfoData(distroSrcinfo)
+	if err != nil {
+		t.Fatalf("ParseSrcinfoData: unexpected error: %v", err)
+	}
+
+	encoded, err := Encode(si)
+	if err != nil {
+		t.Fatalf("Encode: unexpected error: %v", err)
+	}
+
+	if !strings.Contains(string(encoded), "makedepends__fedora = gcc-fedora") {
+		t.Errorf("encoded output missing makedepends__fedora:\n%s", encoded)
+	}
+
+	if !strings.Contains(string(encoded), "source_x86_64__fedora = foo-x86_64-fedora.tar.gz") {
+		t.Errorf("encoded output missing composed source_x86_64__fedora:\n%s", encoded)
+	}
+
+	reparsed, err := ParseSrcinfoData(string(encoded))
+	if err != nil {
+		t.Fatalf("ParseSrcinfoData (re-parse): unexpected error: %v", err)
+	}
+
+	if reparsed.ForDistro("fedora").Pkgdesc != "fedora desc" {
+		t.Errorf("round-tripped ForDistro(\"fedora\").Pkgdesc = %q, want %q", reparsed.ForDistro("fedora").Pkgdesc, "fedora desc")
+	}
+}
@@ -0,0 +1,214 @@
+package srcinfo
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"sort"
+)
+
+// Encode serializes si back into canonical .SRCINFO text, matching the
+// format produced by makepkg --printsrcinfo: a pkgbase header with
+// tab-indented fields, followed by one pkgname section per split package
+// containing only the fields that package overrides. Encode is the
+// inverse of ParseSrcinfoData -- re-parsing its output reproduces an
+// equivalent Srcinfo. Fields declared with a "__<distro>" suffix are
+// emitted back verbatim.
+func Encode(si *Srcinfo) ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	if err := EncodeTo(buf, si); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// EncodeTo writes si to w in canonical .SRCINFO form. See Encode.
+func EncodeTo(w io.Writer, si *Srcinfo) error {
+	bw := bufio.NewWriter(w)
+
+	writeKeyValue(bw, "pkgbase", si.Pkgbase)
+	writeBaseFields(bw, &si.PackageBase, &si.Package, "")
+
+	for _, distro := range sortedBaseDistros(si.PackageBase.DistroOverrides) {
+		override := si.PackageBase.DistroOverrides[distro]
+		writeBaseOnlyFields(bw, &override, distro)
+	}
+
+	for _, distro := range sortedPackageDistros(si.Package.DistroOverrides) {
+		override := si.Package.DistroOverrides[distro]
+		writeSharedFields(bw, &override, distro)
+	}
+
+	for n, pkgname := range si.Pkgnames {
+		bw.WriteByte('\n')
+		writeKeyValue(bw, "pkgname", pkgname)
+		writePackageFields(bw, &si.Packages[n], "")
+
+		for _, distro := range sortedPackageDistros(si.Packages[n].DistroOverrides) {
+			override := si.Packages[n].DistroOverrides[distro]
+			writeSharedFields(bw, &override, distro)
+		}
+	}
+
+	return bw.Flush()
+}
+
+// sortedBaseDistros returns the keys of a PackageBase DistroOverrides
+// map in a stable order, so Encode output is byte-reproducible.
+func sortedBaseDistros(overrides map[string]PackageBase) []string {
+	distros := make([]string, 0, len(overrides))
+	for distro := range overrides {
+		distros = append(distros, distro)
+	}
+
+	sort.Strings(distros)
+
+	return distros
+}
+
+// sortedPackageDistros returns the keys of a Package DistroOverrides
+// map in a stable order, so Encode output is byte-reproducible.
+func sortedPackageDistros(overrides map[string]Package) []string {
+	distros := make([]string, 0, len(overrides))
+	for distro := range overrides {
+		distros = append(distros, distro)
+	}
+
+	sort.Strings(distros)
+
+	return distros
+}
+
+// writeBaseFields writes the pkgbase section: every PackageBase field and
+// every global Package field, in the order makepkg --printsrcinfo uses.
+func writeBaseFields(bw *bufio.Writer, base *PackageBase, pkg *Package, distro string) {
+	writeIndentedValue(bw, "pkgdesc", pkg.Pkgdesc, distro)
+	writeVersionFields(bw, base, distro)
+	writeIndentedValue(bw, "url", pkg.URL, distro)
+	writeIndentedValue(bw, "install", pkg.Install, distro)
+	writeIndentedValue(bw, "changelog", pkg.Changelog, distro)
+	writeIndentedList(bw, "arch", pkg.Arch, distro)
+	writeIndentedList(bw, "groups", pkg.Groups, distro)
+	writeIndentedList(bw, "license", pkg.License, distro)
+	writeIndentedArchList(bw, "checkdepends", base.CheckDepends, distro)
+	writeIndentedArchList(bw, "makedepends", base.MakeDepends, distro)
+	writeIndentedArchList(bw, "depends", pkg.Depends, distro)
+	writeIndentedArchList(bw, "optdepends", pkg.OptDepends, distro)
+	writeIndentedArchList(bw, "provides", pkg.Provides, distro)
+	writeIndentedArchList(bw, "conflicts", pkg.Conflicts, distro)
+	writeIndentedArchList(bw, "replaces", pkg.Replaces, distro)
+	writeIndentedList(bw, "noextract", base.NoExtract, distro)
+	writeIndentedList(bw, "options", pkg.Options, distro)
+	writeIndentedList(bw, "backup", pkg.Backup, distro)
+	writeSourceFields(bw, base, distro)
+}
+
+// writeVersionFields writes the PackageBase version fields, which
+// makepkg places at the very top of the pkgbase section, right after
+// pkgdesc.
+func writeVersionFields(bw *bufio.Writer, base *PackageBase, distro string) {
+	writeIndentedValue(bw, "pkgver", base.Pkgver, distro)
+	writeIndentedValue(bw, "pkgrel", base.Pkgrel, distro)
+	writeIndentedValue(bw, "epoch", base.Epoch, distro)
+}
+
+// writeSourceFields writes the PackageBase source/checksum fields, which
+// makepkg places at the very end of the pkgbase section.
+func writeSourceFields(bw *bufio.Writer, base *PackageBase, distro string) {
+	writeIndentedArchList(bw, "source", base.Source, distro)
+	writeIndentedList(bw, "validpgpkeys", base.ValidPGPKeys, distro)
+	writeIndentedArchList(bw, "md5sums", base.MD5Sums, distro)
+	writeIndentedArchList(bw, "sha1sums", base.SHA1Sums, distro)
+	writeIndentedArchList(bw, "sha224sums", base.SHA224Sums, distro)
+	writeIndentedArchList(bw, "sha256sums", base.SHA256Sums, distro)
+	writeIndentedArchList(bw, "sha384sums", base.SHA384Sums, distro)
+	writeIndentedArchList(bw, "sha512sums", base.SHA512Sums, distro)
+}
+
+// writeBaseOnlyFields writes every PackageBase-only field for a distro
+// override block, in the same relative order they appear within
+// writeBaseFields.
+func writeBaseOnlyFields(bw *bufio.Writer, base *PackageBase, distro string) {
+	writeVersionFields(bw, base, distro)
+	writeIndentedArchList(bw, "checkdepends", base.CheckDepends, distro)
+	writeIndentedArchList(bw, "makedepends", base.MakeDepends, distro)
+	writeIndentedList(bw, "noextract", base.NoExtract, distro)
+	writeSourceFields(bw, base, distro)
+}
+
+// writePackageFields writes a pkgname section: only the fields pkg
+// overrides, in the same relative order as writeBaseFields.
+func writePackageFields(bw *bufio.Writer, pkg *Package, distro string) {
+	writeSharedFields(bw, pkg, distro)
+}
+
+// writeSharedFields writes the Package fields that may be overridden in
+// either the pkgbase header or a package_<pkgname> function.
+func writeSharedFields(bw *bufio.Writer, pkg *Package, distro string) {
+	writeIndentedValue(bw, "pkgdesc", pkg.Pkgdesc, distro)
+	writeIndentedValue(bw, "url", pkg.URL, distro)
+	writeIndentedValue(bw, "install", pkg.Install, distro)
+	writeIndentedValue(bw, "changelog", pkg.Changelog, distro)
+	writeIndentedList(bw, "arch", pkg.Arch, distro)
+	writeIndentedList(bw, "groups", pkg.Groups, distro)
+	writeIndentedList(bw, "license", pkg.License, distro)
+	writeIndentedArchList(bw, "depends", pkg.Depends, distro)
+	writeIndentedArchList(bw, "optdepends", pkg.OptDepends, distro)
+	writeIndentedArchList(bw, "provides", pkg.Provides, distro)
+	writeIndentedArchList(bw, "conflicts", pkg.Conflicts, distro)
+	writeIndentedArchList(bw, "replaces", pkg.Replaces, distro)
+	writeIndentedList(bw, "options", pkg.Options, distro)
+	writeIndentedList(bw, "backup", pkg.Backup, distro)
+}
+
+func writeKeyValue(bw *bufio.Writer, key, value string) {
+	bw.WriteString(key)
+	bw.WriteString(" = ")
+	bw.WriteString(value)
+	bw.WriteByte('\n')
+}
+
+func writeIndentedValue(bw *bufio.Writer, key, value, distro string) {
+	if value == "" {
+		return
+	}
+
+	bw.WriteByte('\t')
+	writeKeyValue(bw, withDistro(key, distro), value)
+}
+
+func writeIndentedList(bw *bufio.Writer, key string, values []string, distro string) {
+	for _, value := range values {
+		bw.WriteByte('\t')
+		writeKeyValue(bw, withDistro(key, distro), value)
+	}
+}
+
+func writeIndentedArchList(bw *bufio.Writer, key string, values []ArchString, distro string) {
+	for _, value := range values {
+		bw.WriteByte('\t')
+		writeKeyValue(bw, withDistro(archKey(key, value.Arch), distro), value.Value)
+	}
+}
+
+// archKey reattaches an architecture suffix to key, mirroring the way
+// getArchFromKey splits it off during parsing.
+func archKey(key, arch string) string {
+	if arch == "" {
+		return key
+	}
+
+	return key + "_" + arch
+}
+
+// withDistro reattaches a "__<distro>" suffix to key, mirroring the way
+// splitDistroFromKey splits it off during parsing.
+func withDistro(key, distro string) string {
+	if distro == "" {
+		return key
+	}
+
+	return key + "__" + distro
+}
@@ -0,0 +1,123 @@
+package srcinfo
+
+import "testing"
+
+// wantEncoded is a hand-written, known-good .SRCINFO matching the field
+// order makepkg --printsrcinfo uses, for a pkgbase with two split
+// packages. TestEncodeFieldOrder asserts Encode's byte output against it literally,
+// since round-tripping through ParseSrcinfoData alone would not catch an
+// ordering regression: re-parsing does not care what order scalar fields
+// were declared in.
+const wantEncoded = `pkgbase = gdc-bin
+	pkgdesc = base desc
+	pkgver = 6.3.0
+	pkgrel = 1
+	epoch = 2
+	url = https://gdcproject.org/
+	arch = i686
+	arch = x86_64
+	license = GPL
+	makedepends = gcc
+	depends = glibc
+	noextract = extra.tar.gz
+	options = !strip
+	source = gdc.tar.gz
+	source_x86_64 = gdc-x86_64.tar.gz
+	validpgpkeys = ABCDEF0123456789ABCDEF0123456789ABCDEF01
+	md5sums = aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa
+	md5sums_x86_64 = bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb
+
+pkgname = gdc-bin
+	pkgdesc = Compiler for D programming language
+	depends = perl
+
+pkgname = libgphobos
+	pkgdesc = Standard library for D programming language
+`
+
+func buildSampleSrcinfo() *Srcinfo {
+	si := &Srcinfo{
+		PackageBase: PackageBase{
+			Pkgbase: "gdc-bin",
+			Pkgver:  "6.3.0",
+			Pkgrel:  "1",
+			Epoch:   "2",
+			Source: []ArchString{
+				{Value: "gdc.tar.gz"},
+				{Arch: "x86_64", Value: "gdc-x86_64.tar.gz"},
+			},
+			ValidPGPKeys: []string{"ABCDEF0123456789ABCDEF0123456789ABCDEF01"},
+			MD5Sums: []ArchString{
+				{Value: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"},
+				{Arch: "x86_64", Value: "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"},
+			},
+			NoExtract:   []string{"extra.tar.gz"},
+			MakeDepends: []ArchString{{Value: "gcc"}},
+		},
+		Package: Package{
+			Pkgdesc: "base desc",
+			URL:     "https://gdcproject.org/",
+			Arch:    []string{"i686", "x86_64"},
+			License: []string{"GPL"},
+			Depends: []ArchString{{Value: "glibc"}},
+			Options: []string{"!strip"},
+		},
+	}
+
+	si.Pkgnames = []string{"gdc-bin", "libgphobos"}
+	si.Packages = []Package{
+		{
+			Pkgdesc: "Compiler for D programming language",
+			Depends: []ArchString{{Value: "perl"}},
+		},
+		{
+			Pkgdesc: "Standard library for D programming language",
+		},
+	}
+
+	return si
+}
+
+func TestEncodeFieldOrder(t *testing.T) {
+	si := buildSampleSrcinfo()
+
+	got, err := Encode(si)
+	if err != nil {
+		t.Fatalf("Encode: unexpected error: %v", err)
+	}
+
+	if string(got) != wantEncoded {
+		t.Fatalf("Encode output mismatch:\ngot:\n%s\nwant:\n%s", got, wantEncoded)
+	}
+}
+
+func TestEncodeParseRoundTrip(t *testing.T) {
+	si := buildSampleSrcinfo()
+
+	encoded, err := Encode(si)
+	if err != nil {
+		t.Fatalf("Encode: unexpected error: %v", err)
+	}
+
+	reparsed, err := ParseSrcinfoData(string(encoded))
+	if err != nil {
+		t.Fatalf("ParseSrcinfoData: unexpected error: %v", err)
+	}
+
+	if reparsed.Pkgbase != si.Pkgbase || reparsed.Pkgver != si.Pkgver || reparsed.Epoch != si.Epoch {
+		t.Errorf("round-tripped PackageBase scalars = %+v, want to match original", reparsed.PackageBase)
+	}
+
+	if len(reparsed.Packages) != 2 || reparsed.Packages[0].Pkgdesc != "Compiler for D programming language" {
+		t.Fatalf("round-tripped Packages = %+v, want the original two split packages", reparsed.Packages)
+	}
+
+	reencoded, err := Encode(reparsed)
+	if err != nil {
+		t.Fatalf("Encode (second pass): unexpected error: %v", err)
+	}
+
+	if string(reencoded) != wantEncoded {
+		t.Fatalf("re-encoded output mismatch after round-trip:\ngot:\n%s\nwant:\n%s", reencoded, wantEncoded)
+	}
+}
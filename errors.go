@@ -0,0 +1,70 @@
+package srcinfo
+
+import "fmt"
+
+// ErrorCode stably identifies the category of a ParseError, so callers
+// can match on it programmatically instead of parsing Message.
+type ErrorCode string
+
+const (
+	ErrNoEquals           ErrorCode = "no-equals"
+	ErrKeyEmpty           ErrorCode = "key-empty"
+	ErrValueEmpty         ErrorCode = "value-empty"
+	ErrFieldBeforePkgbase ErrorCode = "field-before-pkgbase"
+	ErrPkgbaseRepeated    ErrorCode = "pkgbase-repeated"
+	ErrKeyAfterPkgname    ErrorCode = "key-after-pkgname"
+	ErrDuplicatePkgname   ErrorCode = "duplicate-pkgname"
+	ErrUnknownKey         ErrorCode = "unknown-key"
+	ErrUnknownArch        ErrorCode = "unknown-arch"
+)
+
+// Severity distinguishes a Warning diagnostic -- which Parser.Parse
+// records but never aborts on, even in strict mode -- from an Error.
+type Severity int
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+)
+
+// ParseError is a single malformed-line finding produced by Parser.Parse.
+// Unlike the legacy ParseSrcinfo/ParseSrcinfoData, which stop at the
+// first error, a Parser run with WithStrict(false) accumulates one
+// ParseError per offending line and keeps going.
+type ParseError struct {
+	Code     ErrorCode
+	Severity Severity
+
+	Line   int // 1-indexed line number within the input
+	Column int // 1-indexed column of the first non-blank character of the line
+	Offset int // byte offset of the start of the line within the input
+
+	Raw     string // the raw line, minus its line terminator
+	Message string
+}
+
+// Error implements the error interface.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// codedErr is the lightweight error setField and its helpers return.
+// Parser.Parse fills in the positional fields once it knows which line
+// produced it, turning it into a ParseError.
+type codedErr struct {
+	code ErrorCode
+	msg  string
+	warn bool
+}
+
+func (e *codedErr) Error() string { return e.msg }
+
+func newCodedErr(code ErrorCode, format string, args ...interface{}) error {
+	return &codedErr{code: code, msg: fmt.Sprintf(format, args...)}
+}
+
+// newWarnErr builds a codedErr that Parser.Parse records as a
+// SeverityWarning ParseError instead of aborting a strict parse.
+func newWarnErr(code ErrorCode, format string, args ...interface{}) error {
+	return &codedErr{code: code, msg: fmt.Sprintf(format, args...), warn: true}
+}
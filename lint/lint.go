@@ -0,0 +1,85 @@
+// Package lint validates a parsed srcinfo.Srcinfo against the set of
+// rules makepkg and the AUR itself enforce on .SRCINFO content, such as
+// pkgver format, sums/source alignment and SPDX license names. It does
+// not re-implement parsing; it only inspects an already-parsed Srcinfo.
+package lint
+
+import "github.com/Jguer/go-srcinfo"
+
+// Severity classifies how serious a Diagnostic is.
+type Severity int
+
+const (
+	// Error indicates the srcinfo violates a rule that makepkg or the
+	// AUR would reject outright.
+	Error Severity = iota
+	// Warning indicates a rule that is not strictly enforced but is
+	// likely a mistake.
+	Warning
+)
+
+// String implements fmt.Stringer.
+func (s Severity) String() string {
+	switch s {
+	case Error:
+		return "error"
+	case Warning:
+		return "warning"
+	default:
+		return "unknown"
+	}
+}
+
+// Diagnostic is a single finding reported by a Rule. Location is limited
+// to Field: a parsed srcinfo.Srcinfo retains no source line numbers, so
+// there is no line to report here. Lint a srcinfo.Parser's
+// []srcinfo.ParseError diagnostics separately if you need those.
+type Diagnostic struct {
+	Severity Severity
+	RuleID   string
+	Message  string
+	Field    string
+}
+
+// Rule inspects a srcinfo.Srcinfo and reports any Diagnostics it finds.
+// Callers may implement Rule themselves to extend the ruleset used by
+// Lint with project-specific checks.
+type Rule interface {
+	// ID is the stable rule identifier attached to every Diagnostic the
+	// rule produces, e.g. "pkgver-format".
+	ID() string
+	// Check inspects si and returns the Diagnostics found, if any.
+	Check(si *srcinfo.Srcinfo) []Diagnostic
+}
+
+// defaultRules is the set of rules Lint runs unless the caller supplies
+// its own via LintWithRules.
+var defaultRules = []Rule{
+	pkgverRule{},
+	pkgrelRule{},
+	epochRule{},
+	sumsLengthRule{},
+	archSourceRule{},
+	validPGPKeysRule{},
+	licenseRule{},
+	duplicatePkgnameRule{},
+	constraintSyntaxRule{},
+}
+
+// Lint runs the default ruleset against si and returns every Diagnostic
+// found, in rule-registration order.
+func Lint(si *srcinfo.Srcinfo) []Diagnostic {
+	return LintWithRules(si, defaultRules)
+}
+
+// LintWithRules runs rules against si instead of the default ruleset,
+// letting callers add to or replace the rules Lint runs.
+func LintWithRules(si *srcinfo.Srcinfo, rules []Rule) []Diagnostic {
+	var diagnostics []Diagnostic
+
+	for _, rule := range rules {
+		diagnostics = append(diagnostics, rule.Check(si)...)
+	}
+
+	return diagnostics
+}
@@ -0,0 +1,326 @@
+package lint
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Jguer/go-srcinfo"
+)
+
+// pkgverRule checks that pkgver contains neither '-' nor ':', both of
+// which makepkg forbids because they are used as version separators.
+type pkgverRule struct{}
+
+func (pkgverRule) ID() string { return "pkgver-format" }
+
+func (r pkgverRule) Check(si *srcinfo.Srcinfo) []Diagnostic {
+	if strings.ContainsAny(si.Pkgver, "-:") {
+		return []Diagnostic{{
+			Severity: Error,
+			RuleID:   r.ID(),
+			Message:  "pkgver must not contain '-' or ':'",
+			Field:    "pkgver",
+		}}
+	}
+
+	return nil
+}
+
+// pkgrelRule checks that pkgrel is a positive integer or an "n.m" style
+// subrelease, as makepkg requires.
+type pkgrelRule struct{}
+
+func (pkgrelRule) ID() string { return "pkgrel-format" }
+
+var pkgrelPattern = regexp.MustCompile(`^[1-9][0-9]*(\.[1-9][0-9]*)?$`)
+
+func (r pkgrelRule) Check(si *srcinfo.Srcinfo) []Diagnostic {
+	if !pkgrelPattern.MatchString(si.Pkgrel) {
+		return []Diagnostic{{
+			Severity: Error,
+			RuleID:   r.ID(),
+			Message:  "pkgrel must be a positive integer or an \"n.m\" subrelease",
+			Field:    "pkgrel",
+		}}
+	}
+
+	return nil
+}
+
+// epochRule checks that epoch, when set, is a non-negative integer.
+type epochRule struct{}
+
+func (epochRule) ID() string { return "epoch-format" }
+
+func (r epochRule) Check(si *srcinfo.Srcinfo) []Diagnostic {
+	if si.Epoch == "" {
+		return nil
+	}
+
+	n, err := strconv.Atoi(si.Epoch)
+	if err != nil || n < 0 {
+		return []Diagnostic{{
+			Severity: Error,
+			RuleID:   r.ID(),
+			Message:  "epoch must be a non-negative integer",
+			Field:    "epoch",
+		}}
+	}
+
+	return nil
+}
+
+// sumsLengthRule checks that every sums array has the same number of
+// entries, per architecture, as the source array.
+type sumsLengthRule struct{}
+
+func (sumsLengthRule) ID() string { return "sums-length" }
+
+func (r sumsLengthRule) Check(si *srcinfo.Srcinfo) []Diagnostic {
+	var diagnostics []Diagnostic
+
+	sets := map[string][]srcinfo.ArchString{
+		"md5sums":    si.MD5Sums,
+		"sha1sums":   si.SHA1Sums,
+		"sha224sums": si.SHA224Sums,
+		"sha256sums": si.SHA256Sums,
+		"sha384sums": si.SHA384Sums,
+		"sha512sums": si.SHA512Sums,
+	}
+
+	for field, sums := range sets {
+		if len(sums) == 0 {
+			continue
+		}
+
+		if !archCountsEqual(countByArch(sums), countByArch(si.Source)) {
+			diagnostics = append(diagnostics, Diagnostic{
+				Severity: Error,
+				RuleID:   r.ID(),
+				Message:  field + " length must match source length per architecture",
+				Field:    field,
+			})
+		}
+	}
+
+	return diagnostics
+}
+
+// countByArch tallies how many entries of values fall under each
+// architecture, including the empty "arch-independent" bucket.
+func countByArch(values []srcinfo.ArchString) map[string]int {
+	counts := make(map[string]int)
+
+	for _, value := range values {
+		counts[value.Arch]++
+	}
+
+	return counts
+}
+
+// archCountsEqual reports whether a and b, as built by countByArch,
+// contain exactly the same set of architectures each with the same
+// count. Maps in Go are only comparable to nil, so this must be done
+// key-by-key rather than with "==" or "!=".
+func archCountsEqual(a, b map[string]int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for arch, count := range a {
+		if b[arch] != count {
+			return false
+		}
+	}
+
+	return true
+}
+
+// archSourceRule checks that every architecture declared in arch= has a
+// matching source_<arch> entry, if the package uses arch-suffixed
+// sources at all.
+type archSourceRule struct{}
+
+func (archSourceRule) ID() string { return "arch-source-match" }
+
+func (r archSourceRule) Check(si *srcinfo.Srcinfo) []Diagnostic {
+	hasArchSource := false
+
+	for _, src := range si.Source {
+		if src.Arch != "" {
+			hasArchSource = true
+			break
+		}
+	}
+
+	if !hasArchSource {
+		return nil
+	}
+
+	declared := make(map[string]bool)
+	for _, arch := range si.Package.Arch {
+		declared[arch] = true
+	}
+
+	present := make(map[string]bool)
+	for _, src := range si.Source {
+		if src.Arch != "" {
+			present[src.Arch] = true
+		}
+	}
+
+	var diagnostics []Diagnostic
+
+	for arch := range declared {
+		if arch == "any" || present[arch] {
+			continue
+		}
+
+		diagnostics = append(diagnostics, Diagnostic{
+			Severity: Error,
+			RuleID:   r.ID(),
+			Message:  "arch \"" + arch + "\" has no matching source_" + arch + " entry",
+			Field:    "source_" + arch,
+		})
+	}
+
+	return diagnostics
+}
+
+// validPGPKeysRule checks that every validpgpkeys entry is a 40
+// hexadecimal character fingerprint.
+type validPGPKeysRule struct{}
+
+func (validPGPKeysRule) ID() string { return "validpgpkeys-format" }
+
+var fingerprintPattern = regexp.MustCompile(`^[0-9A-Fa-f]{40}$`)
+
+func (r validPGPKeysRule) Check(si *srcinfo.Srcinfo) []Diagnostic {
+	var diagnostics []Diagnostic
+
+	for _, key := range si.ValidPGPKeys {
+		if !fingerprintPattern.MatchString(key) {
+			diagnostics = append(diagnostics, Diagnostic{
+				Severity: Error,
+				RuleID:   r.ID(),
+				Message:  "validpgpkeys entry \"" + key + "\" is not a 40 hex character fingerprint",
+				Field:    "validpgpkeys",
+			})
+		}
+	}
+
+	return diagnostics
+}
+
+// licenseRule checks that every license entry is a known SPDX
+// identifier, begins with CustomPrefix, or is in the configurable
+// Allowlist.
+type licenseRule struct {
+	// Allowlist is matched against, in addition to the SPDX list, for
+	// packages using non-standard license identifiers.
+	Allowlist map[string]bool
+}
+
+// NewLicenseRule builds the license-spdx Rule with allowlist added to
+// the built-in SPDX list, for packages that declare a license outside
+// SPDX without using the CustomPrefix convention. Pass it to
+// LintWithRules, which runs only the rules it is given; Lint always
+// uses the license rule without an allowlist.
+func NewLicenseRule(allowlist map[string]bool) Rule {
+	return licenseRule{Allowlist: allowlist}
+}
+
+func (licenseRule) ID() string { return "license-spdx" }
+
+// CustomPrefix is the prefix makepkg packages use for licenses that are
+// not registered with SPDX, e.g. "custom:MIT-like".
+const CustomPrefix = "custom:"
+
+func (r licenseRule) Check(si *srcinfo.Srcinfo) []Diagnostic {
+	var diagnostics []Diagnostic
+
+	for _, license := range si.Package.License {
+		if strings.HasPrefix(license, CustomPrefix) {
+			continue
+		}
+
+		if spdxLicenses[license] || r.Allowlist[license] {
+			continue
+		}
+
+		diagnostics = append(diagnostics, Diagnostic{
+			Severity: Warning,
+			RuleID:   r.ID(),
+			Message:  "license \"" + license + "\" is not a recognised SPDX identifier",
+			Field:    "license",
+		})
+	}
+
+	return diagnostics
+}
+
+// duplicatePkgnameRule checks that no pkgname is declared more than once.
+type duplicatePkgnameRule struct{}
+
+func (duplicatePkgnameRule) ID() string { return "duplicate-pkgname" }
+
+func (r duplicatePkgnameRule) Check(si *srcinfo.Srcinfo) []Diagnostic {
+	seen := make(map[string]bool)
+	var diagnostics []Diagnostic
+
+	for _, pkgname := range si.Pkgnames {
+		if seen[pkgname] {
+			diagnostics = append(diagnostics, Diagnostic{
+				Severity: Error,
+				RuleID:   r.ID(),
+				Message:  "pkgname \"" + pkgname + "\" is declared more than once",
+				Field:    "pkgname",
+			})
+
+			continue
+		}
+
+		seen[pkgname] = true
+	}
+
+	return diagnostics
+}
+
+// constraintSyntaxRule checks that provides/conflicts/replaces entries
+// using a version constraint use one of the operators pacman supports.
+type constraintSyntaxRule struct{}
+
+func (constraintSyntaxRule) ID() string { return "constraint-syntax" }
+
+var constraintPattern = regexp.MustCompile(`^[^<>=]+(<=|>=|<|>|=)[^<>=]+$`)
+
+func (r constraintSyntaxRule) Check(si *srcinfo.Srcinfo) []Diagnostic {
+	var diagnostics []Diagnostic
+
+	fields := map[string][]srcinfo.ArchString{
+		"provides":  si.Package.Provides,
+		"conflicts": si.Package.Conflicts,
+		"replaces":  si.Package.Replaces,
+	}
+
+	for field, entries := range fields {
+		for _, entry := range entries {
+			value := entry.Value
+			if !strings.ContainsAny(value, "<>=") {
+				continue
+			}
+
+			if !constraintPattern.MatchString(value) {
+				diagnostics = append(diagnostics, Diagnostic{
+					Severity: Error,
+					RuleID:   r.ID(),
+					Message:  field + " entry \"" + value + "\" has an invalid version constraint",
+					Field:    field,
+				})
+			}
+		}
+	}
+
+	return diagnostics
+}
@@ -0,0 +1,70 @@
+package lint
+
+import (
+	"testing"
+
+	srcinfo "github.com/Jguer/go-srcinfo"
+)
+
+// TestSumsLengthRuleMatchingCounts is a regression test for a bug where
+// sumsLengthRule.Check compared two map[string]int with !=, which does
+// not compile: maps are only comparable to nil.
+func TestSumsLengthRuleMatchingCounts(t *testing.T) {
+	si := &srcinfo.Srcinfo{
+		PackageBase: srcinfo.PackageBase{
+			Source: []srcinfo.ArchString{
+				{Arch: "i686", Value: "foo-i686.tar.gz"},
+				{Arch: "x86_64", Value: "foo-x86_64.tar.gz"},
+			},
+			SHA256Sums: []srcinfo.ArchString{
+				{Arch: "i686", Value: "i686sum"},
+				{Arch: "x86_64", Value: "x86_64sum"},
+			},
+		},
+	}
+
+	if diags := (sumsLengthRule{}).Check(si); len(diags) != 0 {
+		t.Fatalf("Check = %+v, want no diagnostics for matching per-arch counts", diags)
+	}
+}
+
+func TestSumsLengthRuleMismatchedCounts(t *testing.T) {
+	si := &srcinfo.Srcinfo{
+		PackageBase: srcinfo.PackageBase{
+			Source: []srcinfo.ArchString{
+				{Arch: "i686", Value: "foo-i686.tar.gz"},
+				{Arch: "x86_64", Value: "foo-x86_64.tar.gz"},
+			},
+			SHA256Sums: []srcinfo.ArchString{
+				{Arch: "i686", Value: "i686sum"},
+			},
+		},
+	}
+
+	diags := (sumsLengthRule{}).Check(si)
+	if len(diags) != 1 {
+		t.Fatalf("len(Check(...)) = %d, want 1 for a missing x86_64 sha256sums entry", len(diags))
+	}
+
+	if diags[0].Field != "sha256sums" {
+		t.Errorf("Field = %q, want %q", diags[0].Field, "sha256sums")
+	}
+}
+
+func TestNewLicenseRuleAllowlist(t *testing.T) {
+	si := &srcinfo.Srcinfo{
+		Package: srcinfo.Package{
+			License: []string{"MyCompanyLicense"},
+		},
+	}
+
+	rule := NewLicenseRule(map[string]bool{"MyCompanyLicense": true})
+
+	if diags := rule.Check(si); len(diags) != 0 {
+		t.Fatalf("Check = %+v, want no diagnostics: license is in the allowlist", diags)
+	}
+
+	if diags := (licenseRule{}).Check(si); len(diags) != 1 {
+		t.Fatalf("Check = %+v, want a single diagnostic without an allowlist", diags)
+	}
+}
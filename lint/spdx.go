@@ -0,0 +1,34 @@
+package lint
+
+// spdxLicenses is the subset of the SPDX license list (spdx.org/licenses)
+// that pacman packages overwhelmingly use. It is not exhaustive; callers
+// that need a license outside this set should either add it via
+// the allowlist of NewLicenseRule or use the CustomPrefix convention.
+var spdxLicenses = map[string]bool{
+	"Apache-2.0":   true,
+	"Apache-1.1":   true,
+	"BSD-2-Clause": true,
+	"BSD-3-Clause": true,
+	"BSL-1.0":      true,
+	"CC0-1.0":      true,
+	"CC-BY-4.0":    true,
+	"CC-BY-SA-4.0": true,
+	"EPL-1.0":      true,
+	"EPL-2.0":      true,
+	"GPL-1.0":      true,
+	"GPL-2.0":      true,
+	"GPL-2.0+":     true,
+	"GPL-3.0":      true,
+	"GPL-3.0+":     true,
+	"ISC":          true,
+	"LGPL-2.0":     true,
+	"LGPL-2.1":     true,
+	"LGPL-2.1+":    true,
+	"LGPL-3.0":     true,
+	"LGPL-3.0+":    true,
+	"MIT":          true,
+	"MPL-1.1":      true,
+	"MPL-2.0":      true,
+	"Unlicense":    true,
+	"Zlib":         true,
+}
@@ -1,7 +1,9 @@
 package srcinfo
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"strings"
 )
@@ -11,22 +13,186 @@ const (
 	headerPkgbase = -1
 )
 
-// parser is used to track our current state as we parse the srcinfo.
-type parser struct {
+// UnknownKeyPolicy controls how a Parser reacts to a key it does not
+// recognise, letting callers tolerate fields added by newer makepkg
+// releases instead of failing outright.
+type UnknownKeyPolicy int
+
+const (
+	// UnknownKeyError records an ErrUnknownKey ParseError, the
+	// historical behaviour of ParseSrcinfoData.
+	UnknownKeyError UnknownKeyPolicy = iota
+	// UnknownKeyWarn records the key but does not treat it as an error.
+	UnknownKeyWarn
+	// UnknownKeyIgnore silently drops the key.
+	UnknownKeyIgnore
+)
+
+// Option configures a Parser. See WithStrict, WithUnknownKeyPolicy and
+// WithAllowedArches.
+type Option func(*Parser)
+
+// WithStrict controls whether Parser.Parse stops at the first malformed
+// line (strict, the default and the behaviour of ParseSrcinfoData) or
+// collects a ParseError for every offending line and keeps going.
+func WithStrict(strict bool) Option {
+	return func(p *Parser) {
+		p.strict = strict
+	}
+}
+
+// WithUnknownKeyPolicy sets how Parser.Parse reacts to keys it does not
+// recognise. The default is UnknownKeyError.
+func WithUnknownKeyPolicy(policy UnknownKeyPolicy) Option {
+	return func(p *Parser) {
+		p.unknownKeyPolicy = policy
+	}
+}
+
+// WithAllowedArches restricts which "_<arch>" key suffixes are accepted.
+// A key suffixed with an architecture not in arches is reported as
+// ErrUnknownArch. If arches is empty, any suffix is accepted.
+func WithAllowedArches(arches []string) Option {
+	return func(p *Parser) {
+		p.allowedArches = make(map[string]bool, len(arches))
+		for _, arch := range arches {
+			p.allowedArches[arch] = true
+		}
+	}
+}
+
+// Parser reads a .SRCINFO document line-by-line, building a Srcinfo and,
+// when built with WithStrict(false), accumulating a ParseError for every
+// malformed or unrecognised line instead of aborting on the first one.
+type Parser struct {
+	strict           bool
+	unknownKeyPolicy UnknownKeyPolicy
+	allowedArches    map[string]bool
+
 	// headerType tracks the current header we are under. This starts out
 	// at headerNone, until a pkgbase field is found at which point it is
 	// changed to headerPkgbase. When we encounter a pkgname field this
-	// value is the index of the current package we are paring in
+	// value is the index of the current package we are parsing in
 	// srcinfo.Packages.
 	headerType int
 
-	// srcingo is a Pointer to the Srcinfo we are currently building.
+	// srcinfo is a pointer to the Srcinfo we are currently building.
 	srcinfo *Srcinfo
+
+	seenPkgnames map[string]struct{}
 }
 
-func (psr *parser) currentPackage() (*Package, error) {
+// NewParser builds a Parser with strict, fail-fast behaviour and an
+// UnknownKeyError policy unless overridden by opts.
+func NewParser(opts ...Option) *Parser {
+	p := &Parser{
+		strict:           true,
+		unknownKeyPolicy: UnknownKeyError,
+		headerType:       headerNone,
+		srcinfo:          &Srcinfo{},
+		seenPkgnames:     map[string]struct{}{},
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// Parse reads a .SRCINFO document from r. In strict mode (the default)
+// it stops at the first error-severity ParseError, which is the last
+// element of the returned slice (after any warnings recorded before it). In
+// non-strict mode it skips offending lines, keeps parsing, and returns
+// every accumulated ParseError once r is exhausted.
+func (p *Parser) Parse(r io.Reader) (*Srcinfo, []ParseError) {
+	// bufio.Reader.ReadString has no token-size ceiling (unlike
+	// bufio.Scanner's default MaxScanTokenSize) and returns the raw line
+	// with its original terminator, so the offsets below stay accurate
+	// for CRLF input too.
+	reader := bufio.NewReader(r)
+
+	var parseErrors []ParseError
+	lineNo := 0
+	offset := 0
+
+	for {
+		raw, readErr := reader.ReadString('\n')
+
+		if raw != "" {
+			lineNo++
+			lineOffset := offset
+			offset += len(raw)
+
+			trimmed := strings.TrimRight(raw, "\r\n")
+			line := strings.TrimSpace(trimmed)
+
+			if line != "" && !strings.HasPrefix(line, "#") {
+				if err := p.parseLine(line); err != nil {
+					pe := p.toParseError(err, lineNo, lineOffset, trimmed, line)
+					parseErrors = append(parseErrors, pe)
+
+					if p.strict && pe.Severity != SeverityWarning {
+						return p.srcinfo, parseErrors
+					}
+				}
+			}
+		}
+
+		if readErr != nil {
+			break
+		}
+	}
+
+	return p.srcinfo, parseErrors
+}
+
+// toParseError attaches positional information to err, which is always
+// either a *codedErr produced within this package or, defensively, some
+// other error that gets tagged ErrUnknownKey.
+func (p *Parser) toParseError(err error, line, offset int, raw, trimmed string) ParseError {
+	code := ErrUnknownKey
+	severity := SeverityError
+	msg := err.Error()
+
+	if ce, ok := err.(*codedErr); ok {
+		code = ce.code
+		msg = ce.msg
+
+		if ce.warn {
+			severity = SeverityWarning
+		}
+	}
+
+	column := strings.Index(raw, trimmed) + 1
+	if column < 1 {
+		column = 1
+	}
+
+	return ParseError{
+		Code:     code,
+		Severity: severity,
+		Line:     line,
+		Column:   column,
+		Offset:   offset,
+		Raw:      raw,
+		Message:  msg,
+	}
+}
+
+// parseLine handles a single trimmed, non-empty, non-comment line.
+func (p *Parser) parseLine(line string) error {
+	key, value, err := p.splitLine(line)
+	if err != nil {
+		return err
+	}
+
+	return p.setField(key, value)
+}
+
+func (psr *Parser) currentPackage() (*Package, error) {
 	if psr.headerType == headerNone {
-		return nil, fmt.Errorf("Not in pkgbase or pkgname")
+		return nil, newCodedErr(ErrFieldBeforePkgbase, "not in pkgbase or pkgname")
 	} else if psr.headerType == headerPkgbase {
 		return &psr.srcinfo.Package, nil
 	} else {
@@ -34,14 +200,25 @@ func (psr *parser) currentPackage() (*Package, error) {
 	}
 }
 
-func (psr *parser) setField(key, value string) error {
+// setField handles a "key = value" line. Fields using the "__<distro>"
+// suffix (e.g. depends__debian) are routed to setDistroField; everything
+// else is handled directly here.
+func (psr *Parser) setField(key, value string) error {
+	baseKey, distro := splitDistroFromKey(key)
+	if distro != "" {
+		return psr.setDistroField(distro, baseKey, value)
+	}
+
+	return psr.setPlainField(key, value)
+}
+
+func (psr *Parser) setPlainField(key, value string) error {
 	pkgbase := &psr.srcinfo.PackageBase
-	seenPkgnames := map[string]struct{}{}
 
 	switch key {
 	case "pkgbase":
 		if psr.headerType != headerNone {
-			return fmt.Errorf("key \"%s\" can not occur after a pkgbase or pkgname", key)
+			return newCodedErr(ErrPkgbaseRepeated, "key \"%s\" can not occur after a pkgbase or pkgname", key)
 		}
 
 		pkgbase.Pkgbase = value
@@ -49,12 +226,12 @@ func (psr *parser) setField(key, value string) error {
 		return nil
 	case "pkgname":
 		if psr.headerType == headerNone {
-			return fmt.Errorf("key \"%s\" can not occur before pkgbase", key)
+			return newCodedErr(ErrFieldBeforePkgbase, "key \"%s\" can not occur before pkgbase", key)
 		}
-		if _, ok := seenPkgnames[value]; ok {
-			return fmt.Errorf("pkgname \"%s\" can not occur more than once", key)
+		if _, ok := psr.seenPkgnames[value]; ok {
+			return newCodedErr(ErrDuplicatePkgname, "pkgname \"%s\" can not occur more than once", value)
 		}
-		seenPkgnames[value] = struct{}{}
+		psr.seenPkgnames[value] = struct{}{}
 
 		pkgbase.Pkgnames = append(pkgbase.Pkgnames, value)
 		psr.srcinfo.Packages = append(psr.srcinfo.Packages, Package{})
@@ -63,7 +240,7 @@ func (psr *parser) setField(key, value string) error {
 	}
 
 	if psr.headerType == headerNone {
-		return fmt.Errorf("key \"%s\" can not occur before pkgbase or pkgname", key)
+		return newCodedErr(ErrFieldBeforePkgbase, "key \"%s\" can not occur before pkgbase or pkgname", key)
 	}
 
 	pkg, err := psr.currentPackage()
@@ -73,8 +250,20 @@ func (psr *parser) setField(key, value string) error {
 
 	found := true
 
-	// pkgbase only
-	switch key {
+	// pkgbase only. Check placement before parsing the value, so a
+	// misplaced field is reported as such even if its value would also
+	// fail arch validation. Matched on the base key so arch-suffixed
+	// variants, e.g. "source_x86_64", are still recognised.
+	switch baseKeyFromKey(key) {
+	case "pkgver", "pkgrel", "epoch", "source", "validpgpkeys", "noextract",
+		"md5sums", "sha1sums", "sha224sums", "sha256sums", "sha384sums", "sha512sums",
+		"makedepends", "checkdepends":
+		if psr.headerType != headerPkgbase {
+			return newCodedErr(ErrKeyAfterPkgname, "key \"%s\" can not occur after pkgname", key)
+		}
+	}
+
+	switch baseKeyFromKey(key) {
 	case "pkgver":
 		pkgbase.Pkgver = value
 	case "pkgrel":
@@ -82,41 +271,74 @@ func (psr *parser) setField(key, value string) error {
 	case "epoch":
 		pkgbase.Epoch = value
 	case "source":
-		pkgbase.Source = append(pkgbase.Source, makeArchString(key, value))
+		as, err := psr.makeArchString(key, value)
+		if err != nil {
+			return err
+		}
+		pkgbase.Source = append(pkgbase.Source, as)
 	case "validpgpkeys":
 		pkgbase.ValidPGPKeys = append(pkgbase.ValidPGPKeys, value)
 	case "noextract":
 		pkgbase.NoExtract = append(pkgbase.NoExtract, value)
 	case "md5sums":
-		pkgbase.MD5Sums = append(pkgbase.MD5Sums, makeArchString(key, value))
+		as, err := psr.makeArchString(key, value)
+		if err != nil {
+			return err
+		}
+		pkgbase.MD5Sums = append(pkgbase.MD5Sums, as)
 	case "sha1sums":
-		pkgbase.SHA1Sums = append(pkgbase.SHA1Sums, makeArchString(key, value))
+		as, err := psr.makeArchString(key, value)
+		if err != nil {
+			return err
+		}
+		pkgbase.SHA1Sums = append(pkgbase.SHA1Sums, as)
 	case "sha224sums":
-		pkgbase.SHA224Sums = append(pkgbase.SHA224Sums, makeArchString(key, value))
+		as, err := psr.makeArchString(key, value)
+		if err != nil {
+			return err
+		}
+		pkgbase.SHA224Sums = append(pkgbase.SHA224Sums, as)
 	case "sha256sums":
-		pkgbase.SHA256Sums = append(pkgbase.SHA256Sums, makeArchString(key, value))
+		as, err := psr.makeArchString(key, value)
+		if err != nil {
+			return err
+		}
+		pkgbase.SHA256Sums = append(pkgbase.SHA256Sums, as)
 	case "sha384sums":
-		pkgbase.SHA384Sums = append(pkgbase.SHA384Sums, makeArchString(key, value))
+		as, err := psr.makeArchString(key, value)
+		if err != nil {
+			return err
+		}
+		pkgbase.SHA384Sums = append(pkgbase.SHA384Sums, as)
 	case "sha512sums":
-		pkgbase.SHA512Sums = append(pkgbase.SHA512Sums, makeArchString(key, value))
+		as, err := psr.makeArchString(key, value)
+		if err != nil {
+			return err
+		}
+		pkgbase.SHA512Sums = append(pkgbase.SHA512Sums, as)
 	case "makedepends":
-		pkgbase.MakeDepends = append(pkgbase.MakeDepends, makeArchString(key, value))
+		as, err := psr.makeArchString(key, value)
+		if err != nil {
+			return err
+		}
+		pkgbase.MakeDepends = append(pkgbase.MakeDepends, as)
 	case "checkdepends":
-		pkgbase.CheckDepends = append(pkgbase.CheckDepends, makeArchString(key, value))
+		as, err := psr.makeArchString(key, value)
+		if err != nil {
+			return err
+		}
+		pkgbase.CheckDepends = append(pkgbase.CheckDepends, as)
 	default:
 		found = false
 	}
 
 	if found {
-		if psr.headerType != headerPkgbase {
-			return fmt.Errorf("key \"%s\" can not occur after pkgname", key)
-		}
-
 		return nil
 	}
 
-	// pkgbase or pkgname
-	switch key {
+	// pkgbase or pkgname. Matched on the base key so arch-suffixed
+	// variants, e.g. "depends_x86_64", are still recognised.
+	switch baseKeyFromKey(key) {
 	case "pkgdesc":
 		pkg.Pkgdesc = value
 	case "url":
@@ -134,76 +356,272 @@ func (psr *parser) setField(key, value string) error {
 	case "backup":
 		pkg.Backup = append(pkg.Backup, value)
 	case "depends":
-		pkg.Depends = append(pkg.Depends, makeArchString(key, value))
+		as, err := psr.makeArchString(key, value)
+		if err != nil {
+			return err
+		}
+		pkg.Depends = append(pkg.Depends, as)
 	case "optdepends":
-		pkg.OptDepends = append(pkg.OptDepends, makeArchString(key, value))
+		as, err := psr.makeArchString(key, value)
+		if err != nil {
+			return err
+		}
+		pkg.OptDepends = append(pkg.OptDepends, as)
 	case "conflicts":
-		pkg.Conflicts = append(pkg.Conflicts, makeArchString(key, value))
+		as, err := psr.makeArchString(key, value)
+		if err != nil {
+			return err
+		}
+		pkg.Conflicts = append(pkg.Conflicts, as)
 	case "provides":
-		pkg.Provides = append(pkg.Provides, makeArchString(key, value))
+		as, err := psr.makeArchString(key, value)
+		if err != nil {
+			return err
+		}
+		pkg.Provides = append(pkg.Provides, as)
 	case "replaces":
-		pkg.Replaces = append(pkg.Replaces, makeArchString(key, value))
+		as, err := psr.makeArchString(key, value)
+		if err != nil {
+			return err
+		}
+		pkg.Replaces = append(pkg.Replaces, as)
 	case "options":
 		pkg.Options = append(pkg.Options, value)
 	default:
-		return fmt.Errorf("Unknown key: \"%s\"", key)
+		return psr.unknownKeyErr(key)
 	}
 
 	return nil
 }
 
-// splitLine splits a key value string in the form of "key = value",
-// whitespace being ignored. The key and the value is returned.
-func (psr *parser) splitLine(line string) (string, string, error) {
-	split := strings.SplitN(line, "=", 2)
+// unknownKeyErr reports key under the Parser's configured
+// UnknownKeyPolicy: as an error, as a warning, or not at all (nil).
+func (psr *Parser) unknownKeyErr(key string) error {
+	switch psr.unknownKeyPolicy {
+	case UnknownKeyIgnore:
+		return nil
+	case UnknownKeyWarn:
+		return newWarnErr(ErrUnknownKey, "unknown key: \"%s\"", key)
+	default:
+		return newCodedErr(ErrUnknownKey, "unknown key: \"%s\"", key)
+	}
+}
 
-	if len(split) != 2 {
-		return "", "", fmt.Errorf("Line does not contain =")
+// splitDistroFromKey splits the "__<distro>" suffix popularized by yap's
+// multi-distro PKGBUILDs off of key, e.g. "depends_x86_64__debian"
+// becomes ("depends_x86_64", "debian"). The distro separator is parsed
+// before any trailing "_<arch>" on the remainder.
+func splitDistroFromKey(key string) (string, string) {
+	idx := strings.Index(key, "__")
+	if idx == -1 {
+		return key, ""
 	}
 
-	key := strings.TrimSpace(split[0])
-	value := strings.TrimSpace(split[1])
+	return key[:idx], key[idx+2:]
+}
 
-	if key == "" {
-		return "", "", fmt.Errorf("Key is empty")
+// baseKeyFromKey strips the "_<arch>" suffix off of key, returning the
+// plain field name, e.g. "depends_x86_64" becomes "depends".
+func baseKeyFromKey(key string) string {
+	split := strings.SplitN(key, "_", 2)
+
+	return split[0]
+}
+
+// setDistroField handles a "key__distro = value" line, storing the
+// value in the matching DistroOverrides entry instead of the plain
+// field. key still carries any arch suffix, e.g. "depends_x86_64".
+func (psr *Parser) setDistroField(distro, key, value string) error {
+	switch baseKeyFromKey(key) {
+	case "pkgver", "pkgrel", "epoch", "source", "validpgpkeys", "noextract",
+		"md5sums", "sha1sums", "sha224sums", "sha256sums", "sha384sums", "sha512sums",
+		"makedepends", "checkdepends":
+		if psr.headerType != headerPkgbase {
+			return newCodedErr(ErrKeyAfterPkgname, "key \"%s\" can not occur after pkgname", key)
+		}
+
+		return psr.setBaseDistroField(distro, key, value)
 	}
 
-	if value == "" {
-		return "", "", fmt.Errorf("value is empty")
+	pkg, err := psr.currentPackage()
+	if err != nil {
+		return err
 	}
 
-	return key, value, nil
+	return psr.setPackageDistroField(pkg, distro, key, value)
 }
 
-func parse(data string) (*Srcinfo, error) {
-	psr := &parser{
-		headerNone,
-		&Srcinfo{},
-	}
+// setBaseDistroField stores a base-only distro override, e.g.
+// makedepends__fedora, on psr.srcinfo.PackageBase.DistroOverrides.
+func (psr *Parser) setBaseDistroField(distro, key, value string) error {
+	pkgbase := &psr.srcinfo.PackageBase
 
-	lines := strings.Split(data, "\n")
+	if pkgbase.DistroOverrides == nil {
+		pkgbase.DistroOverrides = map[string]PackageBase{}
+	}
 
-	for n, line := range lines {
-		line = strings.TrimSpace(line)
+	override := pkgbase.DistroOverrides[distro]
 
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
+	switch baseKeyFromKey(key) {
+	case "pkgver":
+		override.Pkgver = value
+	case "pkgrel":
+		override.Pkgrel = value
+	case "epoch":
+		override.Epoch = value
+	case "source":
+		as, err := psr.makeArchString(key, value)
+		if err != nil {
+			return err
 		}
-
-		key, value, err := psr.splitLine(line)
+		override.Source = append(override.Source, as)
+	case "validpgpkeys":
+		override.ValidPGPKeys = append(override.ValidPGPKeys, value)
+	case "noextract":
+		override.NoExtract = append(override.NoExtract, value)
+	case "md5sums":
+		as, err := psr.makeArchString(key, value)
+		if err != nil {
+			return err
+		}
+		override.MD5Sums = append(override.MD5Sums, as)
+	case "sha1sums":
+		as, err := psr.makeArchString(key, value)
 		if err != nil {
-			return nil, Error(n, line, err.Error())
+			return err
 		}
+		override.SHA1Sums = append(override.SHA1Sums, as)
+	case "sha224sums":
+		as, err := psr.makeArchString(key, value)
+		if err != nil {
+			return err
+		}
+		override.SHA224Sums = append(override.SHA224Sums, as)
+	case "sha256sums":
+		as, err := psr.makeArchString(key, value)
+		if err != nil {
+			return err
+		}
+		override.SHA256Sums = append(override.SHA256Sums, as)
+	case "sha384sums":
+		as, err := psr.makeArchString(key, value)
+		if err != nil {
+			return err
+		}
+		override.SHA384Sums = append(override.SHA384Sums, as)
+	case "sha512sums":
+		as, err := psr.makeArchString(key, value)
+		if err != nil {
+			return err
+		}
+		override.SHA512Sums = append(override.SHA512Sums, as)
+	case "makedepends":
+		as, err := psr.makeArchString(key, value)
+		if err != nil {
+			return err
+		}
+		override.MakeDepends = append(override.MakeDepends, as)
+	case "checkdepends":
+		as, err := psr.makeArchString(key, value)
+		if err != nil {
+			return err
+		}
+		override.CheckDepends = append(override.CheckDepends, as)
+	}
+
+	pkgbase.DistroOverrides[distro] = override
+
+	return nil
+}
+
+// setPackageDistroField stores a shared-field distro override, e.g.
+// pkgdesc__debian or depends_x86_64__debian, on pkg.DistroOverrides.
+func (psr *Parser) setPackageDistroField(pkg *Package, distro, key, value string) error {
+	if pkg.DistroOverrides == nil {
+		pkg.DistroOverrides = map[string]Package{}
+	}
+
+	override := pkg.DistroOverrides[distro]
 
-		err = psr.setField(key, value)
+	switch baseKeyFromKey(key) {
+	case "pkgdesc":
+		override.Pkgdesc = value
+	case "url":
+		override.URL = value
+	case "license":
+		override.License = append(override.License, value)
+	case "install":
+		override.Install = value
+	case "changelog":
+		override.Changelog = value
+	case "groups":
+		override.Groups = append(override.Groups, value)
+	case "arch":
+		override.Arch = append(override.Arch, value)
+	case "backup":
+		override.Backup = append(override.Backup, value)
+	case "depends":
+		as, err := psr.makeArchString(key, value)
+		if err != nil {
+			return err
+		}
+		override.Depends = append(override.Depends, as)
+	case "optdepends":
+		as, err := psr.makeArchString(key, value)
 		if err != nil {
-			return nil, Error(n, line, err.Error())
+			return err
 		}
+		override.OptDepends = append(override.OptDepends, as)
+	case "conflicts":
+		as, err := psr.makeArchString(key, value)
+		if err != nil {
+			return err
+		}
+		override.Conflicts = append(override.Conflicts, as)
+	case "provides":
+		as, err := psr.makeArchString(key, value)
+		if err != nil {
+			return err
+		}
+		override.Provides = append(override.Provides, as)
+	case "replaces":
+		as, err := psr.makeArchString(key, value)
+		if err != nil {
+			return err
+		}
+		override.Replaces = append(override.Replaces, as)
+	case "options":
+		override.Options = append(override.Options, value)
+	default:
+		return psr.unknownKeyErr(key)
+	}
+
+	pkg.DistroOverrides[distro] = override
 
+	return nil
+}
+
+// splitLine splits a key value string in the form of "key = value",
+// whitespace being ignored. The key and the value is returned.
+func (psr *Parser) splitLine(line string) (string, string, error) {
+	split := strings.SplitN(line, "=", 2)
+
+	if len(split) != 2 {
+		return "", "", newCodedErr(ErrNoEquals, "line does not contain =")
 	}
 
-	return psr.srcinfo, nil
+	key := strings.TrimSpace(split[0])
+	value := strings.TrimSpace(split[1])
+
+	if key == "" {
+		return "", "", newCodedErr(ErrKeyEmpty, "key is empty")
+	}
 
+	if value == "" {
+		return "", "", newCodedErr(ErrValueEmpty, "value is empty")
+	}
+
+	return key, value, nil
 }
 
 // getArchFromKey splits up architecture dependent field names, separating
@@ -218,18 +636,37 @@ func getArchFromKey(key string) string {
 	return arch
 }
 
-func makeArchString(key, value string) ArchString {
-	return ArchString{
-		getArchFromKey(key),
-		value,
+// makeArchString splits the architecture suffix off of key and pairs it
+// with value, rejecting it as ErrUnknownArch if the Parser was built
+// with WithAllowedArches and the suffix is not in that set.
+func (psr *Parser) makeArchString(key, value string) (ArchString, error) {
+	arch := getArchFromKey(key)
+
+	if arch != "" && len(psr.allowedArches) != 0 && !psr.allowedArches[arch] {
+		return ArchString{}, newCodedErr(ErrUnknownArch, "unknown architecture: \"%s\"", arch)
+	}
+
+	return ArchString{arch, value}, nil
+}
+
+// parse runs the legacy, strict, fail-fast parse used by
+// ParseSrcinfoData, returning the first malformed line as an error.
+func parse(data string) (*Srcinfo, error) {
+	p := NewParser()
+
+	si, parseErrors := p.Parse(strings.NewReader(data))
+	if len(parseErrors) != 0 {
+		return nil, &parseErrors[0]
 	}
+
+	return si, nil
 }
 
 // ParseSrcinfo parses a srcinfo file as specified by path.
 func ParseSrcinfo(path string) (*Srcinfo, error) {
 	file, err := ioutil.ReadFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("Unable to read file: %s: %s", path, err.Error())
+		return nil, fmt.Errorf("unable to read file: %s: %s", path, err.Error())
 	}
 
 	return ParseSrcinfoData(string(file))
@@ -0,0 +1,135 @@
+package srcinfo
+
+import (
+	"strings"
+	"testing"
+)
+
+const validSrcinfo = `
+pkgbase = gdc-bin
+	pkgver = 6.3.0
+	pkgrel = 1
+	url = https://gdcproject.org/
+	arch = i686
+	arch = x86_64
+	license = GPL
+	source_i686 = gdc-i686.tar.xz
+	md5sums_i686 = cc8dcd66b189245e39296b1382d0dfcc
+	source_x86_64 = gdc-x86_64.tar.xz
+	md5sums_x86_64 = 16d3067ebb3938dba46429a4d9f6178f
+
+pkgname = gdc-bin
+	pkgdesc = Compiler for D programming language
+	depends = perl
+`
+
+func TestParseSrcinfoData(t *testing.T) {
+	si, err := ParseSrcinfoData(validSrcinfo)
+	if err != nil {
+		t.Fatalf("ParseSrcinfoData: unexpected error: %v", err)
+	}
+
+	if si.Pkgbase != "gdc-bin" {
+		t.Errorf("Pkgbase = %q, want %q", si.Pkgbase, "gdc-bin")
+	}
+
+	if si.Pkgver != "6.3.0" {
+		t.Errorf("Pkgver = %q, want %q", si.Pkgver, "6.3.0")
+	}
+
+	if len(si.Packages) != 1 || si.Packages[0].Pkgdesc != "Compiler for D programming language" {
+		t.Fatalf("Packages = %+v, want a single gdc-bin package", si.Packages)
+	}
+}
+
+func TestParseSrcinfoDataMalformedLine(t *testing.T) {
+	_, err := ParseSrcinfoData("pkgbase = foo\nthis line has no equals\n")
+	if err == nil {
+		t.Fatal("ParseSrcinfoData: expected an error for a line with no \"=\"")
+	}
+
+	pe, ok := err.(*ParseError)
+	if !ok {
+		t.Fatalf("error type = %T, want *ParseError", err)
+	}
+
+	if pe.Code != ErrNoEquals {
+		t.Errorf("Code = %q, want %q", pe.Code, ErrNoEquals)
+	}
+
+	if pe.Line != 2 {
+		t.Errorf("Line = %d, want 2", pe.Line)
+	}
+}
+
+func TestParserStrictStopsAtFirstError(t *testing.T) {
+	data := "pkgbase = foo\npkgver = 1.0\nbogus line\npkgrel = 1\n"
+
+	p := NewParser(WithStrict(true))
+
+	si, parseErrors := p.Parse(strings.NewReader(data))
+	if len(parseErrors) != 1 {
+		t.Fatalf("len(parseErrors) = %d, want 1", len(parseErrors))
+	}
+
+	if si.Pkgrel != "" {
+		t.Errorf("Pkgrel = %q, want empty: strict mode should stop before the pkgrel line", si.Pkgrel)
+	}
+}
+
+func TestParserNonStrictAccumulatesErrors(t *testing.T) {
+	data := "pkgbase = foo\nbogus line one\npkgver = 1.0\nbogus line two\npkgrel = 1\n"
+
+	p := NewParser(WithStrict(false))
+
+	si, parseErrors := p.Parse(strings.NewReader(data))
+	if len(parseErrors) != 2 {
+		t.Fatalf("len(parseErrors) = %d, want 2", len(parseErrors))
+	}
+
+	if si.Pkgver != "1.0" || si.Pkgrel != "1" {
+		t.Errorf("Pkgver/Pkgrel = %q/%q, want \"1.0\"/\"1\": non-strict mode should keep parsing past bad lines", si.Pkgver, si.Pkgrel)
+	}
+}
+
+func TestParserUnknownKeyPolicy(t *testing.T) {
+	data := "pkgbase = foo\npkgver = 1.0\npkgrel = 1\nsomekey = value\n"
+
+	t.Run("error", func(t *testing.T) {
+		p := NewParser(WithUnknownKeyPolicy(UnknownKeyError))
+
+		_, parseErrors := p.Parse(strings.NewReader(data))
+		if len(parseErrors) != 1 || parseErrors[0].Code != ErrUnknownKey {
+			t.Fatalf("parseErrors = %+v, want a single ErrUnknownKey", parseErrors)
+		}
+	})
+
+	t.Run("warn", func(t *testing.T) {
+		p := NewParser(WithUnknownKeyPolicy(UnknownKeyWarn))
+
+		_, parseErrors := p.Parse(strings.NewReader(data))
+		if len(parseErrors) != 1 || parseErrors[0].Severity != SeverityWarning {
+			t.Fatalf("parseErrors = %+v, want a single SeverityWarning diagnostic", parseErrors)
+		}
+	})
+
+	t.Run("ignore", func(t *testing.T) {
+		p := NewParser(WithUnknownKeyPolicy(UnknownKeyIgnore))
+
+		_, parseErrors := p.Parse(strings.NewReader(data))
+		if len(parseErrors) != 0 {
+			t.Fatalf("parseErrors = %+v, want none", parseErrors)
+		}
+	})
+}
+
+func TestParserWithAllowedArches(t *testing.T) {
+	data := "pkgbase = foo\npkgver = 1.0\npkgrel = 1\nsource_riscv64 = foo.tar.gz\n"
+
+	p := NewParser(WithAllowedArches([]string{"x86_64"}))
+
+	_, parseErrors := p.Parse(strings.NewReader(data))
+	if len(parseErrors) != 1 || parseErrors[0].Code != ErrUnknownArch {
+		t.Fatalf("parseErrors = %+v, want a single ErrUnknownArch", parseErrors)
+	}
+}
@@ -0,0 +1,127 @@
+// Package repodb builds the desc entries that repo-add writes into a
+// pacman repository database (.db.tar.gz), sourced from a parsed
+// srcinfo.Srcinfo plus the metadata of an already-built package file.
+// It lets a Go-based package registry host a pacman-compatible repo
+// without shelling out to repo-add.
+//
+// This is a deliberately reduced scope compared to repo-add itself: it
+// folds the %DEPENDS%/%OPTDEPENDS%/etc. fields repo-add normally writes
+// to a separate "depends" tar entry into the same "desc" blob, and does
+// not produce a "files" entry at all, since the file list repo-add
+// reads off the built package archive has no equivalent in a Srcinfo.
+package repodb
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/Jguer/go-srcinfo"
+)
+
+// PackageFile carries the metadata repo-add computes from an actual
+// built package file, which cannot be derived from the srcinfo alone.
+type PackageFile struct {
+	Filename string
+	Arch     string
+	CSIZE    int64
+	ISIZE    int64
+	SHA256   string
+	PGPSIG   string
+}
+
+// BuildDescEntry renders the desc entry repo-add would generate for
+// pkgname, in the exact %KEY%/value block order pacman expects. Fields
+// that may vary by architecture (DEPENDS, PROVIDES, ...) are filtered
+// down to meta.Arch plus arch-independent entries, matching what was
+// actually built into meta.Filename.
+func BuildDescEntry(si *srcinfo.Srcinfo, pkgname string, meta PackageFile) ([]byte, error) {
+	pkg, err := si.ForArch(pkgname, meta.Arch)
+	if err != nil {
+		return nil, fmt.Errorf("repodb: %w", err)
+	}
+
+	buf := &bytes.Buffer{}
+
+	writeBlock(buf, "FILENAME", meta.Filename)
+	writeBlock(buf, "NAME", pkgname)
+	writeBlock(buf, "BASE", si.Pkgbase)
+	writeBlock(buf, "VERSION", version(si))
+	writeBlock(buf, "DESC", pkg.Pkgdesc)
+	writeListBlock(buf, "GROUPS", pkg.Groups)
+	writeBlock(buf, "CSIZE", fmt.Sprintf("%d", meta.CSIZE))
+	writeBlock(buf, "ISIZE", fmt.Sprintf("%d", meta.ISIZE))
+	writeBlock(buf, "SHA256SUM", meta.SHA256)
+	writeBlock(buf, "PGPSIG", meta.PGPSIG)
+	writeBlock(buf, "URL", pkg.URL)
+	writeListBlock(buf, "LICENSE", pkg.License)
+	writeBlock(buf, "ARCH", meta.Arch)
+	writeListBlock(buf, "REPLACES", pkg.Replaces)
+	writeListBlock(buf, "CONFLICTS", pkg.Conflicts)
+	writeListBlock(buf, "PROVIDES", pkg.Provides)
+	writeListBlock(buf, "DEPENDS", pkg.Depends)
+	writeListBlock(buf, "OPTDEPENDS", pkg.OptDepends)
+	writeListBlock(buf, "MAKEDEPENDS", pkg.MakeDepends)
+	writeListBlock(buf, "CHECKDEPENDS", pkg.CheckDepends)
+
+	return buf.Bytes(), nil
+}
+
+// version formats the pkgver/pkgrel/epoch fields the way pacman expects
+// them in a package version string: "[epoch:]pkgver-pkgrel".
+func version(si *srcinfo.Srcinfo) string {
+	if si.Epoch != "" {
+		return si.Epoch + ":" + si.Pkgver + "-" + si.Pkgrel
+	}
+
+	return si.Pkgver + "-" + si.Pkgrel
+}
+
+// writeBlock writes a single-value %KEY%/value block, omitting it
+// entirely if value is empty, matching repo-add's behaviour of leaving
+// out unset fields rather than writing them blank.
+func writeBlock(w io.Writer, key, value string) {
+	if value == "" {
+		return
+	}
+
+	fmt.Fprintf(w, "%%%s%%\n%s\n\n", key, value)
+}
+
+// writeListBlock writes a multi-value %KEY%/value block, one value per
+// line, omitting it entirely if values is empty.
+func writeListBlock(w io.Writer, key string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "%%%s%%\n", key)
+
+	for _, value := range values {
+		fmt.Fprintf(w, "%s\n", value)
+	}
+
+	fmt.Fprintln(w)
+}
+
+// AppendToDB appends desc as the "desc" entry of pkgname's directory
+// within the pacman database tar, under the "<pkgname>-<version>/desc"
+// path repo-add uses.
+func AppendToDB(w *tar.Writer, pkgname, pkgVersion string, desc []byte) error {
+	name := pkgname + "-" + pkgVersion + "/desc"
+
+	if err := w.WriteHeader(&tar.Header{
+		Name: name,
+		Mode: 0o644,
+		Size: int64(len(desc)),
+	}); err != nil {
+		return fmt.Errorf("repodb: writing header for %s: %w", name, err)
+	}
+
+	if _, err := w.Write(desc); err != nil {
+		return fmt.Errorf("repodb: writing %s: %w", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package repodb
+
+import (
+	"strings"
+	"testing"
+
+	srcinfo "github.com/Jguer/go-srcinfo"
+)
+
+// TestBuildDescEntryFiltersByArch is a regression test for a bug where
+// DEPENDS (and the other arch-suffixed blocks) included every
+// architecture's entries regardless of meta.Arch.
+func TestBuildDescEntryFiltersByArch(t *testing.T) {
+	si := &srcinfo.Srcinfo{
+		PackageBase: srcinfo.PackageBase{
+			Pkgbase: "foo",
+			Pkgver:  "1.0",
+			Pkgrel:  "1",
+		},
+		Package: srcinfo.Package{
+			Arch: []string{"i686", "x86_64"},
+			Depends: []srcinfo.ArchString{
+				{Arch: "i686", Value: "dep-i686"},
+				{Arch: "x86_64", Value: "dep-x86_64"},
+			},
+		},
+	}
+	si.Pkgnames = []string{"foo"}
+	si.Packages = []srcinfo.Package{{}}
+
+	desc, err := BuildDescEntry(si, "foo", PackageFile{Filename: "foo-1.0-1-x86_64.pkg.tar.zst", Arch: "x86_64"})
+	if err != nil {
+		t.Fatalf("BuildDescEntry: unexpected error: %v", err)
+	}
+
+	if !strings.Contains(string(desc), "dep-x86_64") {
+		t.Errorf("desc missing dep-x86_64:\n%s", desc)
+	}
+
+	if strings.Contains(string(desc), "dep-i686") {
+		t.Errorf("desc contains dep-i686, which does not belong to the x86_64 build:\n%s", desc)
+	}
+}
@@ -37,6 +37,12 @@ type Package struct {
 	Options    []string
 	Install    string
 	Changelog  string
+
+	// DistroOverrides holds fields declared with a "__<distro>" suffix,
+	// e.g. pkgdesc__debian, keyed by distro name. Use ForDistro to
+	// resolve the global Package for a specific distro; ForDistro does
+	// not read the DistroOverrides of the entries of Packages.
+	DistroOverrides map[string]Package
 }
 
 // PackageBase describes the fields of a pkgbuild that may not be overwritten
@@ -58,6 +64,12 @@ type PackageBase struct {
 	SHA512Sums   []ArchString
 	MakeDepends  []ArchString
 	CheckDepends []ArchString
+
+	// DistroOverrides holds base-only fields declared with a
+	// "__<distro>" suffix, e.g. makedepends__fedora, keyed by distro
+	// name. Use BaseForDistro to resolve a PackageBase for a specific
+	// distro.
+	DistroOverrides map[string]PackageBase
 }
 
 // Srcinfo represents a full srcinfo. All global fields are defined here while
@@ -89,6 +101,103 @@ func (si *Srcinfo) SplitPackage(pkgname string) (*Package, error) {
 	return nil, fmt.Errorf("Package \"%s\" is not part of this package base", pkgname)
 }
 
+// ForDistro resolves the global Package for the given distro, falling
+// back to the fields declared without a distro suffix wherever the
+// distro has not overridden them, using the same fallback rules as
+// SplitPackage. Distro overrides declared in a pkgname section (those
+// on the entries of Packages) are not applied.
+//
+// Note slice values will be passed by reference, it is not recommended you
+// modify this struct after it is returned.
+func (si *Srcinfo) ForDistro(distro string) *Package {
+	override, ok := si.Package.DistroOverrides[distro]
+	if !ok {
+		pkg := si.Package
+		return &pkg
+	}
+
+	return mergeSplitPackage(&si.Package, &override)
+}
+
+// BaseForDistro resolves the PackageBase for the given distro, falling
+// back to the fields declared without a distro suffix wherever the
+// distro has not overridden them.
+//
+// Note slice values will be passed by reference, it is not recommended you
+// modify this struct after it is returned.
+func (si *Srcinfo) BaseForDistro(distro string) *PackageBase {
+	override, ok := si.PackageBase.DistroOverrides[distro]
+	if !ok {
+		base := si.PackageBase
+		return &base
+	}
+
+	return mergeBaseOverride(&si.PackageBase, &override)
+}
+
+func mergeBaseOverride(base, override *PackageBase) *PackageBase {
+	merged := &PackageBase{}
+	*merged = *base
+
+	if override.Pkgver != "" {
+		merged.Pkgver = override.Pkgver
+	}
+
+	if override.Pkgrel != "" {
+		merged.Pkgrel = override.Pkgrel
+	}
+
+	if override.Epoch != "" {
+		merged.Epoch = override.Epoch
+	}
+
+	if len(override.Source) != 0 {
+		merged.Source = override.Source
+	}
+
+	if len(override.ValidPGPKeys) != 0 {
+		merged.ValidPGPKeys = override.ValidPGPKeys
+	}
+
+	if len(override.NoExtract) != 0 {
+		merged.NoExtract = override.NoExtract
+	}
+
+	if len(override.MD5Sums) != 0 {
+		merged.MD5Sums = override.MD5Sums
+	}
+
+	if len(override.SHA1Sums) != 0 {
+		merged.SHA1Sums = override.SHA1Sums
+	}
+
+	if len(override.SHA224Sums) != 0 {
+		merged.SHA224Sums = override.SHA224Sums
+	}
+
+	if len(override.SHA256Sums) != 0 {
+		merged.SHA256Sums = override.SHA256Sums
+	}
+
+	if len(override.SHA384Sums) != 0 {
+		merged.SHA384Sums = override.SHA384Sums
+	}
+
+	if len(override.SHA512Sums) != 0 {
+		merged.SHA512Sums = override.SHA512Sums
+	}
+
+	if len(override.MakeDepends) != 0 {
+		merged.MakeDepends = override.MakeDepends
+	}
+
+	if len(override.CheckDepends) != 0 {
+		merged.CheckDepends = override.CheckDepends
+	}
+
+	return merged
+}
+
 func mergeSplitPackage(base, split *Package) *Package {
 	pkg := &Package{}
 	*pkg = *base
@@ -153,5 +262,9 @@ func mergeSplitPackage(base, split *Package) *Package {
 		pkg.Install = split.Install
 	}
 
+	if len(split.DistroOverrides) != 0 {
+		pkg.DistroOverrides = split.DistroOverrides
+	}
+
 	return pkg
 }